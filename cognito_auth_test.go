@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// newTestJWKSServer serves a JWKS containing the public half of key under
+// kid, so verifyAccessToken can resolve signatures the same way it would
+// against a real IdP's jwks.json endpoint.
+func newTestJWKSServer(t *testing.T, kid string, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	pub, err := jwk.PublicKeyOf(key)
+	if err != nil {
+		t.Fatalf("jwk.PublicKeyOf: %v", err)
+	}
+	if err := pub.Set(jwk.KeyIDKey, kid); err != nil {
+		t.Fatalf("pub.Set(kid): %v", err)
+	}
+
+	set := jwk.NewSet()
+	if err := set.AddKey(pub); err != nil {
+		t.Fatalf("set.AddKey: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(set)
+	}))
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	const (
+		issuer   = "https://cognito-idp.us-east-1.amazonaws.com/us-east-1_test"
+		clientID = "test-client-id"
+		kid      = "test-kid"
+	)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+
+	server := newTestJWKSServer(t, kid, key)
+	defer server.Close()
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":       issuer,
+			"token_use": "access",
+			"client_id": clientID,
+			"sub":       "user-1",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr bool
+	}{
+		{
+			name: "valid token passes",
+			token: func() string {
+				return signTestToken(t, key, kid, validClaims())
+			},
+		},
+		{
+			name: "bad signature rejected",
+			token: func() string {
+				return signTestToken(t, otherKey, kid, validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer rejected",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://cognito-idp.us-east-1.amazonaws.com/us-east-1_other"
+				return signTestToken(t, key, kid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-access token_use rejected",
+			token: func() string {
+				claims := validClaims()
+				claims["token_use"] = "id"
+				return signTestToken(t, key, kid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong client_id rejected",
+			token: func() string {
+				claims := validClaims()
+				claims["client_id"] = "some-other-client"
+				return signTestToken(t, key, kid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired token rejected",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return signTestToken(t, key, kid, claims)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifyAccessToken(context.Background(), tt.token(), server.URL, issuer, clientID)
+			if tt.wantErr && err == nil {
+				t.Fatalf("verifyAccessToken() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("verifyAccessToken() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestVerifyAccessTokenRejectsMissingToken(t *testing.T) {
+	if _, err := verifyAccessToken(context.Background(), "", "http://unused.invalid", "issuer", "client"); err == nil {
+		t.Fatalf("verifyAccessToken(\"\") = nil, want error")
+	}
+}
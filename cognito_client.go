@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	cognito "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+)
+
+// cognitoClientWrapper wraps the Cognito IDP SDK client and, when the app
+// client is configured with a client secret, transparently computes and
+// attaches the SECRET_HASH parameter Cognito requires on InitiateAuth and
+// RespondToAuthChallenge calls.
+type cognitoClientWrapper struct {
+	sdk          *cognito.Client
+	clientID     string
+	clientSecret string
+}
+
+func newCognitoClientWrapper(sdk *cognito.Client, clientID, clientSecret string) *cognitoClientWrapper {
+	return &cognitoClientWrapper{sdk: sdk, clientID: clientID, clientSecret: clientSecret}
+}
+
+// secretHash computes the base64-encoded HMAC-SHA256 of (username + clientID)
+// keyed by the app client secret, as required by Cognito when the app client
+// has a secret configured.
+func (c *cognitoClientWrapper) secretHash(username string) string {
+	mac := hmac.New(sha256.New, []byte(c.clientSecret))
+	mac.Write([]byte(username + c.clientID))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// withSecretHash adds SECRET_HASH to authParameters when the client secret is
+// configured, keyed off the USERNAME parameter already present.
+func (c *cognitoClientWrapper) withSecretHash(authParameters map[string]string) map[string]string {
+	if c.clientSecret == "" {
+		return authParameters
+	}
+	username := authParameters["USERNAME"]
+	authParameters["SECRET_HASH"] = c.secretHash(username)
+	return authParameters
+}
+
+func (c *cognitoClientWrapper) InitiateAuth(ctx context.Context, authFlow types.AuthFlowType, authParameters map[string]string) (*cognito.InitiateAuthOutput, error) {
+	return c.sdk.InitiateAuth(ctx, &cognito.InitiateAuthInput{
+		AuthFlow:       authFlow,
+		ClientId:       &c.clientID,
+		AuthParameters: c.withSecretHash(authParameters),
+	})
+}
+
+func (c *cognitoClientWrapper) RespondToAuthChallenge(ctx context.Context, challengeName types.ChallengeNameType, challengeResponses map[string]string) (*cognito.RespondToAuthChallengeOutput, error) {
+	return c.sdk.RespondToAuthChallenge(ctx, &cognito.RespondToAuthChallengeInput{
+		ChallengeName:      challengeName,
+		ClientId:           &c.clientID,
+		ChallengeResponses: c.withSecretHash(challengeResponses),
+	})
+}
@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestWithSecretHash(t *testing.T) {
+	c := newCognitoClientWrapper(nil, "client-id", "client-secret")
+
+	params := map[string]string{
+		"USERNAME":      "jane@example.com",
+		"REFRESH_TOKEN": "some-refresh-token",
+	}
+	got := c.withSecretHash(params)
+
+	want := c.secretHash("jane@example.com")
+	if got["SECRET_HASH"] != want {
+		t.Fatalf("SECRET_HASH = %q, want %q", got["SECRET_HASH"], want)
+	}
+}
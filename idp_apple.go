@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	appleJWKSURL = "https://appleid.apple.com/auth/keys"
+	appleIssuer  = "https://appleid.apple.com"
+)
+
+// AppleVerifier verifies Apple-issued ID tokens (ES256) against Apple's
+// published JWKS, fetched through the shared JWKS cache.
+type AppleVerifier struct {
+	clientID string
+}
+
+func NewAppleVerifier(clientID string) *AppleVerifier {
+	return &AppleVerifier{clientID: clientID}
+}
+
+func (a *AppleVerifier) Name() string     { return "apple" }
+func (a *AppleVerifier) Audience() string { return a.clientID }
+
+func (a *AppleVerifier) Verify(ctx context.Context, idToken, nonce string) (Claims, error) {
+	token, err := jwt.Parse(idToken, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+
+		keyID, ok := t.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("missing kid header in token")
+		}
+
+		key, err := sharedJWKS.LookupKey(ctx, appleJWKSURL, keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		var pubkey interface{}
+		if err := key.Raw(&pubkey); err != nil {
+			return nil, err
+		}
+		return pubkey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("apple token validation failed: %w", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token claims")
+	}
+
+	// Check expiry and audience
+	if exp, ok := claims["exp"].(float64); ok {
+		if time.Unix(int64(exp), 0).Before(time.Now()) {
+			return nil, errors.New("token expired")
+		}
+	}
+	if aud, ok := claims["aud"].(string); ok && aud != a.clientID {
+		return nil, fmt.Errorf("invalid audience: %s", aud)
+	}
+
+	result := Claims(claims)
+	if err := verifyFreshnessAndReplay(result, appleIssuer, nonce); err != nil {
+		return nil, fmt.Errorf("apple token rejected: %w", err)
+	}
+
+	return result, nil
+}
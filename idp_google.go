@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/idtoken"
+)
+
+// GoogleVerifier verifies Google-issued ID tokens via the official
+// idtoken validator, which handles fetching and caching Google's signing
+// keys internally.
+type GoogleVerifier struct {
+	clientID string
+}
+
+func NewGoogleVerifier(clientID string) *GoogleVerifier {
+	return &GoogleVerifier{clientID: clientID}
+}
+
+func (g *GoogleVerifier) Name() string     { return "google" }
+func (g *GoogleVerifier) Audience() string { return g.clientID }
+
+const googleIssuer = "https://accounts.google.com"
+
+func (g *GoogleVerifier) Verify(ctx context.Context, idToken, nonce string) (Claims, error) {
+	payload, err := idtoken.Validate(ctx, idToken, g.clientID)
+	if err != nil {
+		return nil, err
+	}
+	claims := Claims(payload.Claims)
+
+	if err := verifyFreshnessAndReplay(claims, googleIssuer, nonce); err != nil {
+		return nil, fmt.Errorf("google token rejected: %w", err)
+	}
+
+	return claims, nil
+}
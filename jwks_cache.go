@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+const defaultJWKSTTL = time.Hour
+
+// JWKSCache fetches and caches per-issuer JWKS, refreshing them in the
+// background so verifying a token never blocks on a network round trip to
+// the IdP's key endpoint. On a kid cache-miss (e.g. the IdP rotated its
+// signing keys since the last background refresh) it forces an immediate
+// synchronous refetch before giving up.
+type JWKSCache struct {
+	cache *jwk.Cache
+	ttl   time.Duration
+
+	mu         sync.RWMutex
+	registered map[string]bool
+}
+
+// NewJWKSCache creates a JWKSCache whose background refresh interval is
+// ttl (defaulting to one hour when ttl <= 0).
+func NewJWKSCache(ctx context.Context, ttl time.Duration) *JWKSCache {
+	if ttl <= 0 {
+		ttl = defaultJWKSTTL
+	}
+	return &JWKSCache{
+		cache:      jwk.NewCache(ctx),
+		ttl:        ttl,
+		registered: make(map[string]bool),
+	}
+}
+
+// Register starts background refreshing for the given JWKS URL. It is
+// idempotent — calling it more than once for the same URL is a no-op.
+func (c *JWKSCache) Register(url string) error {
+	c.mu.RLock()
+	already := c.registered[url]
+	c.mu.RUnlock()
+	if already {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registered[url] {
+		return nil
+	}
+	if err := c.cache.Register(url, jwk.WithMinRefreshInterval(c.ttl)); err != nil {
+		return fmt.Errorf("failed to register JWKS %s: %w", url, err)
+	}
+	c.registered[url] = true
+	return nil
+}
+
+// Get returns the cached key set for url, registering it first if
+// necessary.
+func (c *JWKSCache) Get(ctx context.Context, url string) (jwk.Set, error) {
+	if err := c.Register(url); err != nil {
+		return nil, err
+	}
+	return c.cache.Get(ctx, url)
+}
+
+// LookupKey returns the key identified by kid in url's key set. If kid
+// isn't found in the cached set — typically because the IdP rotated its
+// signing keys since the last background refresh — it forces a
+// synchronous refetch and retries once before failing.
+func (c *JWKSCache) LookupKey(ctx context.Context, url, kid string) (jwk.Key, error) {
+	set, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if key, found := set.LookupKeyID(kid); found {
+		return key, nil
+	}
+
+	set, err = c.cache.Refresh(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("no matching JWK for kid %q, and refresh failed: %w", kid, err)
+	}
+	key, found := set.LookupKeyID(kid)
+	if !found {
+		return nil, fmt.Errorf("no matching JWK for kid: %s", kid)
+	}
+	return key, nil
+}
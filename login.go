@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cognito "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+)
+
+type IdPLoginRequest struct {
+	IDToken string `json:"id_token"`
+	Nonce   string `json:"nonce,omitempty"`
+}
+
+type IdPLoginResponse struct {
+	AccessToken   string `json:"access_token,omitempty"`
+	IDToken       string `json:"id_token,omitempty"`
+	RefreshToken  string `json:"refresh_token,omitempty"`
+	Message       string `json:"message,omitempty"`
+	ChallengeName string `json:"challenge_name,omitempty"`
+	Session       string `json:"session,omitempty"`
+}
+
+// loginHandler verifies the ID token against the IdPVerifier registered
+// for {provider}, then exchanges the verified identity for Cognito tokens.
+// Adding a new IdP only requires registering a verifier in
+// setupIdPVerifiers; this handler does not change.
+func loginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	w.Header().Set("Content-Type", "application/json")
+
+	provider := r.PathValue("provider")
+	verifier, err := idpVerifierFor(provider)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error": "%v"}`, err), http.StatusNotFound)
+		return
+	}
+
+	var req IdPLoginRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims, err := verifier.Verify(ctx, req.IDToken, req.Nonce)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error": "%s token verification failed: %v"}`, provider, err), http.StatusUnauthorized)
+		return
+	}
+
+	email := fmt.Sprintf("%v", claims["email"])
+	fmt.Printf("✅ Verified %s login for: %s\n", provider, email)
+
+	if userPoolID == "" {
+		// No Cognito integration configured at all: the IdP login itself
+		// still succeeded, so report it rather than failing the request.
+		json.NewEncoder(w).Encode(IdPLoginResponse{
+			Message: fmt.Sprintf("%s login verified for %s (Cognito not configured)", provider, email),
+		})
+		return
+	}
+
+	resp, err := cognitoLoginFor(ctx, provider, claims, req.IDToken)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error": "cognito auth error: %v"}`, err), http.StatusUnauthorized)
+		return
+	}
+
+	// CUSTOM_AUTH (Facebook, Microsoft, generic OIDC) goes through a
+	// Lambda-trigger challenge flow: AuthenticationResult is only populated
+	// once the challenge sequence completes, so a normal first response
+	// carries ChallengeName/Session instead of tokens.
+	if resp.AuthenticationResult == nil {
+		json.NewEncoder(w).Encode(IdPLoginResponse{
+			ChallengeName: string(resp.ChallengeName),
+			Session:       aws.ToString(resp.Session),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(IdPLoginResponse{
+		AccessToken:  aws.ToString(resp.AuthenticationResult.AccessToken),
+		IDToken:      aws.ToString(resp.AuthenticationResult.IdToken),
+		RefreshToken: aws.ToString(resp.AuthenticationResult.RefreshToken),
+	})
+}
+
+// cognitoLoginFor exchanges a verified federated identity for Cognito
+// tokens, just-in-time provisioning the Cognito user and retrying once if
+// AUTO_PROVISION_USERS is enabled and the user doesn't exist yet.
+func cognitoLoginFor(ctx context.Context, provider string, claims Claims, idToken string) (*cognito.InitiateAuthOutput, error) {
+	email := fmt.Sprintf("%v", claims["email"])
+
+	resp, err := initiateCognitoAuth(ctx, provider, email, idToken)
+	if err != nil && autoProvisionUsers && isUserNotFound(err) {
+		if provisionErr := provisionFederatedUser(ctx, provider, claims); provisionErr != nil {
+			return nil, fmt.Errorf("%w (provisioning failed: %v)", err, provisionErr)
+		}
+		resp, err = initiateCognitoAuth(ctx, provider, email, idToken)
+	}
+	return resp, err
+}
+
+// initiateCognitoAuth exchanges a verified identity for Cognito tokens.
+// Google is federated natively via Cognito's IDENTITY_PROVIDER parameter;
+// every other provider goes through CUSTOM_AUTH, the same flow the
+// original Apple-only handler used.
+func initiateCognitoAuth(ctx context.Context, provider, email, idToken string) (*cognito.InitiateAuthOutput, error) {
+	if provider == "google" {
+		return cognitoSvc.InitiateAuth(ctx, types.AuthFlowTypeUserSrpAuth, map[string]string{
+			"IDENTITY_PROVIDER": "Google",
+			"USERNAME":          email,
+			"ID_TOKEN":          idToken,
+		})
+	}
+	return cognitoSvc.InitiateAuth(ctx, types.AuthFlowTypeCustomAuth, map[string]string{
+		"USERNAME": email,
+	})
+}
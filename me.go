@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type MeResponse struct {
+	Sub           string   `json:"sub"`
+	Email         string   `json:"email,omitempty"`
+	CognitoGroups []string `json:"cognito:groups,omitempty"`
+	ExpiresAt     int64    `json:"expires_at"`
+}
+
+// meHandler returns the caller's identity as decoded from their verified
+// Cognito access token. It must run behind RequireCognitoAuth.
+//
+// Note: Cognito access tokens don't carry an email claim unless a Pre
+// Token Generation Lambda adds one, so Email is typically empty here — it
+// lives on the ID token, which this endpoint doesn't see.
+func meHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := claimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, `{"error": "missing claims"}`, http.StatusUnauthorized)
+		return
+	}
+
+	exp, _ := claims["exp"].(float64)
+	email, _ := claims["email"].(string)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(MeResponse{
+		Sub:           fmt.Sprintf("%v", claims["sub"]),
+		Email:         email,
+		CognitoGroups: stringSlice(claims["cognito:groups"]),
+		ExpiresAt:     int64(exp),
+	})
+}
+
+// stringSlice converts a decoded JSON array claim ([]interface{}) into a
+// []string, returning nil if v isn't such an array.
+func stringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, fmt.Sprintf("%v", item))
+	}
+	return out
+}
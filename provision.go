@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cognito "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+)
+
+var autoProvisionUsers bool
+
+// providerNames maps an IdPVerifier name to the Cognito ProviderName used
+// when linking a federated identity to a Cognito user.
+var providerNames = map[string]string{
+	"google":    "Google",
+	"apple":     "SignInWithApple",
+	"facebook":  "Facebook",
+	"microsoft": "Microsoft",
+	"oidc":      "OIDC",
+}
+
+// provisionFederatedUser just-in-time creates a Cognito user for a
+// federated identity that passed IdP verification but doesn't yet exist
+// in the user pool, then links the external provider identity to it so
+// future logins resolve to the same Cognito user.
+func provisionFederatedUser(ctx context.Context, provider string, claims Claims) error {
+	email := fmt.Sprintf("%v", claims["email"])
+	sub := fmt.Sprintf("%v", claims["sub"])
+
+	attrs := []types.AttributeType{
+		{Name: aws.String("email"), Value: aws.String(email)},
+		{Name: aws.String("email_verified"), Value: aws.String("true")},
+	}
+	if givenName, ok := claims["given_name"].(string); ok && givenName != "" {
+		attrs = append(attrs, types.AttributeType{Name: aws.String("given_name"), Value: aws.String(givenName)})
+	}
+	if familyName, ok := claims["family_name"].(string); ok && familyName != "" {
+		attrs = append(attrs, types.AttributeType{Name: aws.String("family_name"), Value: aws.String(familyName)})
+	}
+
+	_, err := cognitoSvc.sdk.AdminCreateUser(ctx, &cognito.AdminCreateUserInput{
+		UserPoolId:     aws.String(userPoolID),
+		Username:       aws.String(email),
+		UserAttributes: attrs,
+		MessageAction:  types.MessageActionTypeSuppress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user %s: %w", email, err)
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		return fmt.Errorf("failed to generate password for %s: %w", email, err)
+	}
+	if _, err := cognitoSvc.sdk.AdminSetUserPassword(ctx, &cognito.AdminSetUserPasswordInput{
+		UserPoolId: aws.String(userPoolID),
+		Username:   aws.String(email),
+		Password:   aws.String(password),
+		Permanent:  true,
+	}); err != nil {
+		return fmt.Errorf("failed to set password for %s: %w", email, err)
+	}
+
+	providerName, ok := providerNames[provider]
+	if !ok {
+		return fmt.Errorf("no Cognito provider mapping for %s", provider)
+	}
+	if _, err := cognitoSvc.sdk.AdminLinkProviderForUser(ctx, &cognito.AdminLinkProviderForUserInput{
+		UserPoolId: aws.String(userPoolID),
+		DestinationUser: &types.ProviderUserIdentifierType{
+			ProviderName:           aws.String("Cognito"),
+			ProviderAttributeValue: aws.String(email),
+		},
+		SourceUser: &types.ProviderUserIdentifierType{
+			ProviderName:           aws.String(providerName),
+			ProviderAttributeName:  aws.String("Cognito_Subject"),
+			ProviderAttributeValue: aws.String(sub),
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to link %s identity for %s: %w", provider, email, err)
+	}
+
+	return nil
+}
+
+// randomPassword generates a password meeting Cognito's default complexity
+// policy (it's never used for real sign-in since AdminSetUserPassword makes
+// it permanent but the user authenticates federatively).
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf) + "Aa1!", nil
+}
+
+func isUserNotFound(err error) bool {
+	var nf *types.UserNotFoundException
+	return errors.As(err, &nf)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+)
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	Username     string `json:"username"`
+}
+
+type RefreshResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+// refreshTokenHandler exchanges a previously-issued refresh token for a fresh
+// access/id token pair without requiring the user to re-authenticate with
+// the upstream IdP.
+func refreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	w.Header().Set("Content-Type", "application/json")
+
+	var req RefreshRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &req); err != nil || req.RefreshToken == "" || req.Username == "" {
+		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	// USERNAME is required here even though Cognito doesn't need it to look
+	// up the session — it's what withSecretHash uses to compute SECRET_HASH
+	// for app clients configured with a client secret.
+	resp, err := cognitoSvc.InitiateAuth(ctx, types.AuthFlowTypeRefreshTokenAuth, map[string]string{
+		"REFRESH_TOKEN": req.RefreshToken,
+		"USERNAME":      req.Username,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error": "refresh failed: %v"}`, err), http.StatusUnauthorized)
+		return
+	}
+
+	json.NewEncoder(w).Encode(RefreshResponse{
+		AccessToken: aws.ToString(resp.AuthenticationResult.AccessToken),
+		IDToken:     aws.ToString(resp.AuthenticationResult.IdToken),
+	})
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRefreshTokenHandlerRequiresUsername(t *testing.T) {
+	body := strings.NewReader(`{"refresh_token": "some-refresh-token"}`)
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", body)
+	w := httptest.NewRecorder()
+
+	refreshTokenHandler(w, req)
+
+	// Without USERNAME, withSecretHash would compute SECRET_HASH from an
+	// empty username for any secret-configured app client, so the request
+	// must be rejected before it ever reaches InitiateAuth.
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
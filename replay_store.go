@@ -0,0 +1,73 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ReplayStore records token identifiers that have already been
+// successfully verified so a replayed token can be rejected. The default
+// implementation is an in-memory bounded LRU; ReplayStore can be swapped
+// for a Redis-backed implementation in multi-instance deployments.
+type ReplayStore interface {
+	// SeenOrRemember returns true if key was already recorded (a replay);
+	// otherwise it records key for ttl and returns false.
+	SeenOrRemember(key string, ttl time.Duration) bool
+}
+
+const defaultReplayStoreCapacity = 10000
+
+var sharedReplayStore ReplayStore = newLRUReplayStore(defaultReplayStoreCapacity)
+
+type replayEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// lruReplayStore is a bounded in-memory ReplayStore backed by an LRU list,
+// so a high volume of distinct tokens can't grow the store unbounded.
+// Entries are evicted once the store exceeds capacity or their TTL has
+// elapsed, whichever comes first.
+type lruReplayStore struct {
+	mu       sync.Mutex
+	capacity int
+	list     *list.List
+	index    map[string]*list.Element
+}
+
+func newLRUReplayStore(capacity int) *lruReplayStore {
+	return &lruReplayStore{
+		capacity: capacity,
+		list:     list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+func (s *lruReplayStore) SeenOrRemember(key string, ttl time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.index[key]; ok {
+		entry := el.Value.(*replayEntry)
+		if time.Now().Before(entry.expiresAt) {
+			return true
+		}
+		s.list.Remove(el)
+		delete(s.index, key)
+	}
+
+	el := s.list.PushFront(&replayEntry{key: key, expiresAt: time.Now().Add(ttl)})
+	s.index[key] = el
+
+	for s.list.Len() > s.capacity {
+		oldest := s.list.Back()
+		if oldest == nil {
+			break
+		}
+		s.list.Remove(oldest)
+		delete(s.index, oldest.Value.(*replayEntry).key)
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUReplayStoreSeenOrRemember(t *testing.T) {
+	store := newLRUReplayStore(10)
+
+	if store.SeenOrRemember("jti-1", time.Minute) {
+		t.Fatalf("first SeenOrRemember(jti-1) = true, want false")
+	}
+	if !store.SeenOrRemember("jti-1", time.Minute) {
+		t.Fatalf("second SeenOrRemember(jti-1) = false, want true (replay)")
+	}
+	if store.SeenOrRemember("jti-2", time.Minute) {
+		t.Fatalf("SeenOrRemember(jti-2) = true, want false (distinct key)")
+	}
+}
+
+func TestLRUReplayStoreExpiredEntryIsNotAReplay(t *testing.T) {
+	store := newLRUReplayStore(10)
+
+	store.SeenOrRemember("jti-1", -time.Second)
+
+	if store.SeenOrRemember("jti-1", time.Minute) {
+		t.Fatalf("SeenOrRemember(jti-1) after expiry = true, want false")
+	}
+}
+
+func TestLRUReplayStoreEvictsOverCapacity(t *testing.T) {
+	store := newLRUReplayStore(2)
+
+	store.SeenOrRemember("jti-1", time.Minute)
+	store.SeenOrRemember("jti-2", time.Minute)
+	store.SeenOrRemember("jti-3", time.Minute)
+
+	if store.SeenOrRemember("jti-1", time.Minute) {
+		t.Fatalf("jti-1 should have been evicted once capacity was exceeded")
+	}
+	if !store.SeenOrRemember("jti-3", time.Minute) {
+		t.Fatalf("jti-3 should still be remembered (most recently added)")
+	}
+}
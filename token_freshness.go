@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxIatSkewFuture = 5 * time.Minute
+	defaultMaxIatSkewPast   = 10 * time.Minute
+)
+
+// maxIatSkewFuture/maxIatSkewPast bound how far a token's iat may drift
+// from the server's clock before it's rejected. Both are configurable via
+// env vars since clock skew tolerance is deployment-specific.
+var (
+	maxIatSkewFuture = defaultMaxIatSkewFuture
+	maxIatSkewPast   = defaultMaxIatSkewPast
+)
+
+func init() {
+	if v := durationFromSecondsEnv("MAX_IAT_SKEW_FUTURE_SECONDS"); v > 0 {
+		maxIatSkewFuture = v
+	}
+	if v := durationFromSecondsEnv("MAX_IAT_SKEW_PAST_SECONDS"); v > 0 {
+		maxIatSkewPast = v
+	}
+}
+
+func durationFromSecondsEnv(name string) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// verifyFreshnessAndReplay runs the checks shared by the Apple and Google
+// verifiers beyond signature/audience/expiry: issuer, issued-at clock-skew
+// sanity, optional nonce comparison, and replay detection. nonce is the
+// value the client sent at sign-in; pass "" to skip nonce verification.
+func verifyFreshnessAndReplay(claims Claims, expectedIssuer, nonce string) error {
+	if iss, _ := claims["iss"].(string); iss != expectedIssuer {
+		return fmt.Errorf("invalid issuer: %s", iss)
+	}
+
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return errors.New("missing iat claim")
+	}
+	issuedAt := time.Unix(int64(iat), 0)
+	now := time.Now()
+	if issuedAt.After(now.Add(maxIatSkewFuture)) {
+		return fmt.Errorf("token issued too far in the future: iat=%s", issuedAt)
+	}
+	if issuedAt.Before(now.Add(-maxIatSkewPast)) {
+		return fmt.Errorf("token issued too long ago: iat=%s", issuedAt)
+	}
+
+	if nonce != "" {
+		if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+			return errors.New("nonce mismatch")
+		}
+	}
+
+	exp, _ := claims["exp"].(float64)
+	ttl := time.Until(time.Unix(int64(exp), 0))
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	if sharedReplayStore.SeenOrRemember(replayKeyFor(claims), ttl) {
+		return errors.New("token replay detected")
+	}
+
+	return nil
+}
+
+// replayKeyFor derives the replay-cache key for claims: the token's jti
+// when present, otherwise sub|iat.
+func replayKeyFor(claims Claims) string {
+	if jti, ok := claims["jti"].(string); ok && jti != "" {
+		return jti
+	}
+	return fmt.Sprintf("%v|%v", claims["sub"], claims["iat"])
+}
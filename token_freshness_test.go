@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyFreshnessAndReplay(t *testing.T) {
+	now := time.Now()
+
+	baseClaims := func() Claims {
+		return Claims{
+			"iss": "https://example.test",
+			"iat": float64(now.Unix()),
+			"exp": float64(now.Add(time.Hour).Unix()),
+			"sub": "user-1",
+			"jti": "jti-1",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(Claims)
+		nonce   string
+		wantErr bool
+	}{
+		{
+			name:   "valid token passes",
+			mutate: func(c Claims) {},
+		},
+		{
+			name: "wrong issuer rejected",
+			mutate: func(c Claims) {
+				c["iss"] = "https://evil.test"
+			},
+			wantErr: true,
+		},
+		{
+			name: "iat too far in the future rejected",
+			mutate: func(c Claims) {
+				c["iat"] = float64(now.Add(maxIatSkewFuture + time.Minute).Unix())
+			},
+			wantErr: true,
+		},
+		{
+			name: "iat too far in the past rejected",
+			mutate: func(c Claims) {
+				c["iat"] = float64(now.Add(-(maxIatSkewPast + time.Minute)).Unix())
+			},
+			wantErr: true,
+		},
+		{
+			name: "nonce mismatch rejected",
+			mutate: func(c Claims) {
+				c["nonce"] = "expected-nonce"
+			},
+			nonce:   "different-nonce",
+			wantErr: true,
+		},
+		{
+			name: "matching nonce passes",
+			mutate: func(c Claims) {
+				c["nonce"] = "expected-nonce"
+			},
+			nonce: "expected-nonce",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sharedReplayStore = newLRUReplayStore(defaultReplayStoreCapacity)
+
+			claims := baseClaims()
+			tt.mutate(claims)
+
+			err := verifyFreshnessAndReplay(claims, "https://example.test", tt.nonce)
+			if tt.wantErr && err == nil {
+				t.Fatalf("verifyFreshnessAndReplay() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("verifyFreshnessAndReplay() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestVerifyFreshnessAndReplayRejectsReplayedJTI(t *testing.T) {
+	sharedReplayStore = newLRUReplayStore(defaultReplayStoreCapacity)
+
+	claims := Claims{
+		"iss": "https://example.test",
+		"iat": float64(time.Now().Unix()),
+		"exp": float64(time.Now().Add(time.Hour).Unix()),
+		"sub": "user-1",
+		"jti": "replayed-jti",
+	}
+
+	if err := verifyFreshnessAndReplay(claims, "https://example.test", ""); err != nil {
+		t.Fatalf("first use: verifyFreshnessAndReplay() = %v, want nil", err)
+	}
+	if err := verifyFreshnessAndReplay(claims, "https://example.test", ""); err == nil {
+		t.Fatalf("replay: verifyFreshnessAndReplay() = nil, want error")
+	}
+}